@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+	"strings"
+
+	"github.com/inkeliz/oksvg/svgicon"
+)
+
+// icon pairs a generated symbol name with the parsed icon it came from,
+// plus an optional ViewBox override supplied on the command line.
+type icon struct {
+	symbol  string
+	parsed  *svgicon.SvgIcon
+	viewBox *viewBoxOverride
+}
+
+type viewBoxOverride struct{ X, Y, W, H float64 }
+
+// generate writes a deterministic Go source file declaring one
+// package-level svgicon.SvgIcon variable per icon, sorted by symbol name
+// so reruns over an unchanged directory produce byte-identical output.
+// It also returns one warning for each fill or stroke pattern (e.g. a
+// gradient) that could not be represented as a literal and was therefore
+// omitted from the generated style, plus one more for each path left
+// with neither a fill nor a stroke.
+func generate(pkg string, icons []icon) (string, []string) {
+	sort.Slice(icons, func(i, j int) bool { return icons[i].symbol < icons[j].symbol })
+
+	var b strings.Builder
+	var warnings []string
+	fmt.Fprintf(&b, "// Code generated by svgicongen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	fmt.Fprintf(&b, "import \"github.com/inkeliz/oksvg/svgicon\"\n\n")
+
+	for _, ic := range icons {
+		writeIconVar(&b, ic, &warnings)
+	}
+
+	return b.String(), warnings
+}
+
+func writeIconVar(b *strings.Builder, ic icon, warnings *[]string) {
+	vb := ic.parsed.ViewBox
+	if ic.viewBox != nil {
+		vb.X, vb.Y, vb.W, vb.H = ic.viewBox.X, ic.viewBox.Y, ic.viewBox.W, ic.viewBox.H
+	}
+
+	fmt.Fprintf(b, "var %s = svgicon.SvgIcon{\n", ic.symbol)
+	fmt.Fprintf(b, "\tViewBox: svgicon.ViewBox{X: %g, Y: %g, W: %g, H: %g},\n", vb.X, vb.Y, vb.W, vb.H)
+	fmt.Fprintf(b, "\tTransform: svgicon.Identity,\n")
+	fmt.Fprintf(b, "\tAspectRatio: svgicon.PreserveAspectRatio{Align: %d, MeetOrSlice: %d},\n", ic.parsed.AspectRatio.Align, ic.parsed.AspectRatio.MeetOrSlice)
+	fmt.Fprintf(b, "\tSVGPaths: []svgicon.SvgPath{\n")
+	for i, path := range ic.parsed.SVGPaths {
+		writeSVGPath(b, ic.symbol, i, path, warnings)
+	}
+	fmt.Fprintf(b, "\t},\n}\n\n")
+}
+
+func writeSVGPath(b *strings.Builder, symbol string, index int, path svgicon.SvgPath, warnings *[]string) {
+	fmt.Fprintf(b, "\t\t{\n")
+	if style, ok := styleLiteral(symbol, index, path.PathStyle, warnings); ok {
+		fmt.Fprintf(b, "\t\t\tPathStyle: %s,\n", style)
+	}
+	fmt.Fprintf(b, "\t\t\tPath: svgicon.Path{\n")
+	for _, op := range path.Path {
+		fmt.Fprintf(b, "\t\t\t\t%s,\n", opLiteral(op))
+	}
+	fmt.Fprintf(b, "\t\t\t},\n")
+	fmt.Fprintf(b, "\t\t},\n")
+}
+
+// styleLiteral renders a path's fill and stroke color as a PathStyle
+// literal. Only plain colors are supported: a gradient, or any other
+// Pattern implementation, is reported as a warning and left out of the
+// literal rather than silently dropped. If neither fill nor stroke ends
+// up representable, the caller omits the PathStyle field entirely and
+// this also warns, since that path would otherwise render invisible.
+func styleLiteral(symbol string, index int, style svgicon.PathStyle, warnings *[]string) (string, bool) {
+	var fields []string
+
+	if plain, ok := plainColorLiteral("FillerColor", style.FillerColor); ok {
+		fields = append(fields, plain)
+	} else if style.FillerColor != nil {
+		*warnings = append(*warnings, fmt.Sprintf("%s: path %d: fill pattern %T not supported by svgicongen, omitted", symbol, index, style.FillerColor))
+	}
+
+	if plain, ok := plainColorLiteral("LinerColor", style.LinerColor); ok {
+		fields = append(fields, plain)
+	} else if style.LinerColor != nil {
+		*warnings = append(*warnings, fmt.Sprintf("%s: path %d: stroke pattern %T not supported by svgicongen, omitted", symbol, index, style.LinerColor))
+	}
+
+	if len(fields) == 0 {
+		*warnings = append(*warnings, fmt.Sprintf("%s: path %d: neither fill nor stroke could be represented, path will render invisible", symbol, index))
+		return "", false
+	}
+
+	return "svgicon.PathStyle{" + strings.Join(fields, ", ") + "}", true
+}
+
+func plainColorLiteral(field string, pattern svgicon.Pattern) (string, bool) {
+	plain, ok := pattern.(svgicon.PlainColor)
+	if !ok {
+		return "", false
+	}
+	c := color.NRGBA(plain)
+	return fmt.Sprintf("%s: svgicon.PlainColor{R: %d, G: %d, B: %d, A: %d}", field, c.R, c.G, c.B, c.A), true
+}
+
+func opLiteral(op svgicon.Operation) string {
+	switch op := op.(type) {
+	case svgicon.OpMoveTo:
+		return fmt.Sprintf("svgicon.OpMoveTo{X: %d, Y: %d}", op.X, op.Y)
+	case svgicon.OpLineTo:
+		return fmt.Sprintf("svgicon.OpLineTo{X: %d, Y: %d}", op.X, op.Y)
+	case svgicon.OpQuadTo:
+		return fmt.Sprintf("svgicon.OpQuadTo{{X: %d, Y: %d}, {X: %d, Y: %d}}", op[0].X, op[0].Y, op[1].X, op[1].Y)
+	case svgicon.OpCubicTo:
+		return fmt.Sprintf("svgicon.OpCubicTo{{X: %d, Y: %d}, {X: %d, Y: %d}, {X: %d, Y: %d}}",
+			op[0].X, op[0].Y, op[1].X, op[1].Y, op[2].X, op[2].Y)
+	case svgicon.OpClose:
+		return "svgicon.OpClose{}"
+	default:
+		return "/* unsupported operation */"
+	}
+}
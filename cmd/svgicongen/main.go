@@ -0,0 +1,127 @@
+// Command svgicongen reads a directory of SVG files and emits a Go
+// source file declaring one svgicon.SvgIcon variable per icon, with the
+// icon's Path operations already materialized as Go literals. This
+// moves XML parsing from runtime to build time for applications that
+// ship a fixed icon set.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/inkeliz/oksvg/svgicon"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("svgicongen: ")
+
+	var (
+		pkgName    = flag.String("pkg", "icons", "package name for the generated file")
+		outPath    = flag.String("out", "", "output file (default: stdout)")
+		viewBoxRaw = flag.String("viewbox", "", "comma-separated list of name=x,y,w,h ViewBox overrides")
+	)
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s [flags] <svg-dir>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	overrides, err := parseViewBoxOverrides(*viewBoxRaw)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	icons, err := loadIcons(flag.Arg(0), overrides)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	src, warnings := generate(*pkgName, icons)
+	for _, w := range warnings {
+		log.Print(w)
+	}
+
+	if *outPath == "" {
+		os.Stdout.WriteString(src)
+		return
+	}
+	if err := ioutil.WriteFile(*outPath, []byte(src), 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// loadIcons parses every *.svg file in dir, warning and skipping on a
+// per-file read or parse error instead of aborting the whole run.
+func loadIcons(dir string, overrides map[string]viewBoxOverride) ([]icon, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var icons []icon
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".svg") {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		parsed, err := svgicon.ReadIcon(path, svgicon.WarnErrorMode)
+		if err != nil {
+			log.Printf("skipping %s: %v", path, err)
+			continue
+		}
+
+		base := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		ic := icon{symbol: symbolName(base), parsed: parsed}
+		if vb, ok := overrides[base]; ok {
+			ic.viewBox = &vb
+		}
+		icons = append(icons, ic)
+	}
+	return icons, nil
+}
+
+// parseViewBoxOverrides parses a flag value like
+// "home=0,0,24,24,menu=2,2,20,20" into per-icon overrides keyed by file
+// base name.
+func parseViewBoxOverrides(raw string) (map[string]viewBoxOverride, error) {
+	overrides := make(map[string]viewBoxOverride)
+	if raw == "" {
+		return overrides, nil
+	}
+	fields := strings.Split(raw, ",")
+	if len(fields)%4 != 0 {
+		return nil, fmt.Errorf("svgicongen: -viewbox must be name=x,y,w,h groups, got %d fields", len(fields))
+	}
+	for i := 0; i < len(fields); i += 4 {
+		nameAndX := strings.SplitN(fields[i], "=", 2)
+		if len(nameAndX) != 2 {
+			return nil, fmt.Errorf("svgicongen: malformed -viewbox entry %q", fields[i])
+		}
+		name := nameAndX[0]
+		nums := append([]string{nameAndX[1]}, fields[i+1:i+4]...)
+		var vb viewBoxOverride
+		values := []*float64{&vb.X, &vb.Y, &vb.W, &vb.H}
+		for j, s := range nums {
+			f, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return nil, fmt.Errorf("svgicongen: bad number %q in -viewbox: %w", s, err)
+			}
+			*values[j] = f
+		}
+		overrides[name] = vb
+	}
+	return overrides, nil
+}
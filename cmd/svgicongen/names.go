@@ -0,0 +1,56 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// acronyms lists the initialisms that should stay upper-cased when a
+// file name is turned into an exported Go identifier, e.g. "arrow-url"
+// becomes ArrowURL rather than ArrowUrl.
+var acronyms = map[string]string{
+	"id":   "ID",
+	"url":  "URL",
+	"http": "HTTP",
+	"html": "HTML",
+	"svg":  "SVG",
+	"xml":  "XML",
+	"2d":   "2D",
+	"3d":   "3D",
+	"ui":   "UI",
+}
+
+// symbolName turns a file base name such as "arrow-circle_left" or
+// "24px.arrow" into an exported Go identifier such as ArrowCircleLeft or
+// Icon24pxArrow, splitting on any run of non-alphanumeric characters.
+func symbolName(base string) string {
+	words := strings.FieldsFunc(base, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var b strings.Builder
+	for _, w := range words {
+		lower := strings.ToLower(w)
+		if up, ok := acronyms[lower]; ok {
+			b.WriteString(up)
+			continue
+		}
+		if len(w) == 0 {
+			continue
+		}
+		r := []rune(w)
+		b.WriteRune(unicode.ToUpper(r[0]))
+		for _, c := range r[1:] {
+			b.WriteRune(unicode.ToLower(c))
+		}
+	}
+
+	name := b.String()
+	if name == "" {
+		return "Icon"
+	}
+	if unicode.IsDigit(rune(name[0])) {
+		name = "Icon" + name
+	}
+	return name
+}
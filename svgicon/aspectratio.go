@@ -0,0 +1,94 @@
+package svgicon
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Align selects which edges of the ViewBox are kept flush with the
+// target rectangle once MeetOrSlice has picked a scale factor, mirroring
+// the nine xMin/xMid/xMax x yMin/yMid/yMax combinations of SVG's
+// preserveAspectRatio attribute. AlignNone disables the alignment step
+// entirely (the "none" keyword), stretching the ViewBox to fill the
+// target.
+type Align uint8
+
+const (
+	AlignNone Align = iota
+	AlignXMinYMin
+	AlignXMidYMin
+	AlignXMaxYMin
+	AlignXMinYMid
+	AlignXMidYMid
+	AlignXMaxYMid
+	AlignXMinYMax
+	AlignXMidYMax
+	AlignXMaxYMax
+)
+
+// MeetOrSlice picks how the ViewBox is scaled to fit the target
+// rectangle once aligned.
+type MeetOrSlice uint8
+
+const (
+	// Meet scales the ViewBox down so it fits entirely inside the target,
+	// leaving letterbox/pillarbox space on the other axis.
+	Meet MeetOrSlice = iota
+	// Slice scales the ViewBox up so it covers the target entirely,
+	// cropping whatever overflows on the other axis.
+	Slice
+)
+
+// PreserveAspectRatio is the parsed form of the SVG preserveAspectRatio
+// attribute.
+type PreserveAspectRatio struct {
+	Align       Align
+	MeetOrSlice MeetOrSlice
+}
+
+// DefaultPreserveAspectRatio is "xMidYMid meet", the value SVG specifies
+// when the attribute is absent.
+var DefaultPreserveAspectRatio = PreserveAspectRatio{Align: AlignXMidYMid, MeetOrSlice: Meet}
+
+var alignKeywords = map[string]Align{
+	"none":     AlignNone,
+	"xMinYMin": AlignXMinYMin,
+	"xMidYMin": AlignXMidYMin,
+	"xMaxYMin": AlignXMaxYMin,
+	"xMinYMid": AlignXMinYMid,
+	"xMidYMid": AlignXMidYMid,
+	"xMaxYMid": AlignXMaxYMid,
+	"xMinYMax": AlignXMinYMax,
+	"xMidYMax": AlignXMidYMax,
+	"xMaxYMax": AlignXMaxYMax,
+}
+
+// ParsePreserveAspectRatio parses the value of a preserveAspectRatio
+// attribute, e.g. "xMinYMid meet" or "none". The optional leading
+// "defer" keyword is accepted and ignored, as oksvg has no nested
+// <image>/<svg> elements for it to apply to.
+func ParsePreserveAspectRatio(s string) (PreserveAspectRatio, error) {
+	fields := strings.Fields(s)
+	if len(fields) > 0 && fields[0] == "defer" {
+		fields = fields[1:]
+	}
+	if len(fields) == 0 {
+		return DefaultPreserveAspectRatio, nil
+	}
+	align, ok := alignKeywords[fields[0]]
+	if !ok {
+		return PreserveAspectRatio{}, fmt.Errorf("svgicon: unknown preserveAspectRatio alignment %q", fields[0])
+	}
+	par := PreserveAspectRatio{Align: align, MeetOrSlice: Meet}
+	if len(fields) > 1 {
+		switch fields[1] {
+		case "meet":
+			par.MeetOrSlice = Meet
+		case "slice":
+			par.MeetOrSlice = Slice
+		default:
+			return PreserveAspectRatio{}, fmt.Errorf("svgicon: unknown preserveAspectRatio meetOrSlice %q", fields[1])
+		}
+	}
+	return par, nil
+}
@@ -0,0 +1,15 @@
+package svgicon
+
+// Backend is the pluggable rasterizer abstraction a SvgIcon is drawn
+// through. It extends Driver with Unsupported, so callers that try a
+// backend without full parity for every style feature (e.g. a vector-
+// only implementation lacking some stroke joins) can detect that some
+// part of the drawing was skipped and fall back to a different backend,
+// without having to type-assert to a concrete implementation.
+type Backend interface {
+	Driver
+	// Unsupported returns the first error encountered while drawing
+	// that this Backend could not faithfully render, or nil if
+	// everything drawn so far was fully supported.
+	Unsupported() error
+}
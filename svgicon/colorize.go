@@ -0,0 +1,118 @@
+package svgicon
+
+import "image/color"
+
+// Colorized returns a copy of icon with every solid fill and stroke
+// color set to fill, leaving gradients' shapes (offsets, direction,
+// spread) untouched but recoloring their stops to fill as well. icon
+// itself is left unmodified, so it remains safe to keep rasterizing
+// concurrently while a caller builds color variants of it; the name
+// says "Colorized", not "Colorize", precisely to flag that it returns a
+// new *SvgIcon rather than mutating the receiver.
+func (icon *SvgIcon) Colorized(fill color.Color) *SvgIcon {
+	c := colorToNRGBA(fill)
+	return icon.ColorizedMatching(func(p Pattern) Pattern {
+		if g, ok := p.(Gradient); ok {
+			g.Stops = append([]GradStop(nil), g.Stops...)
+			for i := range g.Stops {
+				g.Stops[i].StopColor = c
+			}
+			return g
+		}
+		return PlainColor(c)
+	})
+}
+
+// ReplacedColors returns a copy of icon with each color found in its
+// styles remapped according to mapping. Colors not present in mapping
+// are left untouched. Gradient stops are matched and replaced stop by
+// stop. icon itself is left unmodified.
+func (icon *SvgIcon) ReplacedColors(mapping map[color.Color]color.Color) *SvgIcon {
+	normalized := make(map[color.NRGBA]color.NRGBA, len(mapping))
+	for from, to := range mapping {
+		normalized[colorToNRGBA(from)] = colorToNRGBA(to)
+	}
+	replace := func(c color.NRGBA) color.NRGBA {
+		if to, ok := normalized[c]; ok {
+			return to
+		}
+		return c
+	}
+	return icon.ColorizedMatching(func(p Pattern) Pattern {
+		switch p := p.(type) {
+		case PlainColor:
+			return PlainColor(replace(color.NRGBA(p)))
+		case Gradient:
+			clone := p
+			clone.Stops = append([]GradStop(nil), p.Stops...)
+			for i, stop := range clone.Stops {
+				clone.Stops[i].StopColor = replace(colorToNRGBA(stop.StopColor))
+			}
+			return clone
+		default:
+			return p
+		}
+	})
+}
+
+// ColorizedMatching gives full control over recoloring: replace is
+// called with every fill and stroke Pattern found in icon's styles
+// (including gradients referenced from defs), and its return value
+// becomes the Pattern used in the returned copy. icon itself is never
+// mutated, so concurrently rasterizing it while building variants with
+// ColorizedMatching is safe; only the returned *SvgIcon carries the new
+// colors.
+func (icon *SvgIcon) ColorizedMatching(replace func(Pattern) Pattern) *SvgIcon {
+	clone := *icon
+
+	clone.SVGPaths = make([]SvgPath, len(icon.SVGPaths))
+	for i, path := range icon.SVGPaths {
+		if path.PathStyle.FillerColor != nil {
+			path.PathStyle.FillerColor = cloneIfGradient(replace(path.PathStyle.FillerColor))
+		}
+		if path.PathStyle.LinerColor != nil {
+			path.PathStyle.LinerColor = cloneIfGradient(replace(path.PathStyle.LinerColor))
+		}
+		clone.SVGPaths[i] = path
+	}
+
+	clone.grads = make(map[string]*Gradient, len(icon.grads))
+	for id, grad := range icon.grads {
+		g := *grad
+		g.Stops = append([]GradStop(nil), grad.Stops...)
+		if p := cloneIfGradient(replace(g)); p != nil {
+			if gg, ok := p.(Gradient); ok {
+				clone.grads[id] = &gg
+			}
+		}
+	}
+
+	return &clone
+}
+
+// cloneIfGradient returns p unchanged, except for Gradient values whose
+// Stops slice is defensively copied so two Patterns never alias the same
+// backing array after a Colorized call.
+func cloneIfGradient(p Pattern) Pattern {
+	if g, ok := p.(Gradient); ok {
+		g.Stops = append([]GradStop(nil), g.Stops...)
+		return g
+	}
+	return p
+}
+
+func colorToNRGBA(c color.Color) color.NRGBA {
+	if c, ok := c.(color.NRGBA); ok {
+		return c
+	}
+	r, g, b, a := c.RGBA()
+	if a == 0 {
+		return color.NRGBA{}
+	}
+	return color.NRGBA{
+		R: uint8((r * 0xff / a)),
+		G: uint8((g * 0xff / a)),
+		B: uint8((b * 0xff / a)),
+		A: uint8(a >> 8),
+	}
+}
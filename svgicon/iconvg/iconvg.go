@@ -0,0 +1,391 @@
+// Package iconvg implements a minimal encoder and decoder for the IconVG
+// binary format (https://github.com/google/iconvg), a compact
+// representation well suited to monochrome-or-simple icons. It lets
+// callers serialize a parsed *svgicon.SvgIcon to a small byte blob and
+// read that blob back, either as an *svgicon.SvgIcon (DecodeIconVG) or
+// by driving a svgicon.Driver directly (Decode).
+//
+// Only path geometry and fill color round-trip. A gradient fill keeps
+// its kind (linear or radial) and its stops' offsets and colors; the
+// decoder gives it a default direction in object-bounding-box units.
+// Stroke color, width, dash and opacity are not encoded.
+package iconvg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image/color"
+
+	"github.com/inkeliz/oksvg/svgicon"
+	"golang.org/x/image/math/fixed"
+)
+
+// magic is the 4-byte header every IconVG file starts with.
+var magic = [4]byte{0x89, 'I', 'V', 'G'}
+
+// Styling opcodes occupy the 0x00-0x7f range.
+const (
+	opSetCReg        byte = 0x00 // set a CREG color register (index byte, RGBA bytes)
+	opSelectGradient byte = 0x01 // select a gradient as the current fill/stroke color
+	opNoFill         byte = 0x02 // start a new, unfilled (stroke-only) path
+)
+
+// Drawing opcodes occupy the 0x80-0xdf range.
+const (
+	opMoveTo     byte = 0x80
+	opLineTo     byte = 0x81
+	opQuadTo     byte = 0x82
+	opQuadToRel  byte = 0x83
+	opCubicTo    byte = 0x84
+	opCubicToRel byte = 0x85
+	opArcTo      byte = 0x86
+	opClose      byte = 0x87
+)
+
+// midViewBox is the metadata chunk ID that carries the ViewBox, the only
+// chunk this package currently requires.
+const midViewBox = 0
+
+// EncodeIconVG serializes icon into an IconVG byte stream: the magic
+// header, the ViewBox metadata chunk, then a styling/drawing opcode for
+// every path and operation in icon.SVGPaths.
+func EncodeIconVG(icon *svgicon.SvgIcon) ([]byte, error) {
+	if icon == nil {
+		return nil, errors.New("iconvg: nil icon")
+	}
+
+	var buf bytes.Buffer
+	buf.Write(magic[:])
+
+	putVarint(&buf, 1) // one metadata chunk: the ViewBox
+	putVarint(&buf, midViewBox)
+	putNumber(&buf, icon.ViewBox.X)
+	putNumber(&buf, icon.ViewBox.Y)
+	putNumber(&buf, icon.ViewBox.X+icon.ViewBox.W)
+	putNumber(&buf, icon.ViewBox.Y+icon.ViewBox.H)
+
+	for creg, path := range icon.SVGPaths {
+		if creg > 0xff {
+			return nil, fmt.Errorf("iconvg: too many paths (%d) for an 8-bit CREG index", len(icon.SVGPaths))
+		}
+		if err := encodeStyle(&buf, byte(creg), path.PathStyle); err != nil {
+			return nil, err
+		}
+		encodePath(&buf, path.Path)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// encodeStyle emits the opcode that starts a new path and, for filled
+// paths, selects its fill before the drawing operations that follow it.
+// Every path gets exactly one such opcode, including unfilled
+// (stroke-only) paths via opNoFill, so the decoder can always tell where
+// one path's operations end and the next one's begin.
+//
+// Only FillerColor is serialized: stroke color, width, dash and opacity
+// are not part of this format yet.
+func encodeStyle(buf *bytes.Buffer, creg byte, style svgicon.PathStyle) error {
+	switch fill := style.FillerColor.(type) {
+	case svgicon.PlainColor:
+		buf.WriteByte(opSetCReg)
+		buf.WriteByte(creg)
+		writeRGBA(buf, color.Color(fill))
+	case svgicon.Gradient:
+		buf.WriteByte(opSelectGradient)
+		buf.WriteByte(creg)
+		encodeGradient(buf, fill)
+	case nil:
+		buf.WriteByte(opNoFill)
+		buf.WriteByte(creg)
+	default:
+		return fmt.Errorf("iconvg: unsupported fill pattern %T", fill)
+	}
+	return nil
+}
+
+func encodeGradient(buf *bytes.Buffer, grad svgicon.Gradient) {
+	isRadial := byte(0)
+	if _, ok := grad.Direction.(svgicon.Radial); ok {
+		isRadial = 1
+	}
+	buf.WriteByte(isRadial)
+	putVarint(buf, int32(len(grad.Stops)))
+	for _, stop := range grad.Stops {
+		putVarint(buf, int32(stop.Offset*(1<<14)))
+		writeRGBA(buf, stop.StopColor)
+	}
+}
+
+// writeRGBA writes c's straight (non-premultiplied) 8-bit channels, to
+// match readRGBA on the decode side. color.Color.RGBA() returns
+// alpha-premultiplied values, so it is not used here directly: for a
+// translucent color that would darken the stored RGB channels instead
+// of round-tripping them.
+func writeRGBA(buf *bytes.Buffer, c color.Color) {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	buf.WriteByte(n.R)
+	buf.WriteByte(n.G)
+	buf.WriteByte(n.B)
+	buf.WriteByte(n.A)
+}
+
+// encodePath walks the already-flattened Path operations, quantizing the
+// fixed.Point26_6 coordinates they carry into the variable-length number
+// encoding.
+func encodePath(buf *bytes.Buffer, path svgicon.Path) {
+	for _, op := range path {
+		switch op := op.(type) {
+		case svgicon.OpMoveTo:
+			buf.WriteByte(opMoveTo)
+			putPoint(buf, fixed.Point26_6(op))
+		case svgicon.OpLineTo:
+			buf.WriteByte(opLineTo)
+			putPoint(buf, fixed.Point26_6(op))
+		case svgicon.OpQuadTo:
+			buf.WriteByte(opQuadTo)
+			putPoint(buf, op[0])
+			putPoint(buf, op[1])
+		case svgicon.OpCubicTo:
+			buf.WriteByte(opCubicTo)
+			putPoint(buf, op[0])
+			putPoint(buf, op[1])
+			putPoint(buf, op[2])
+		case svgicon.OpClose:
+			buf.WriteByte(opClose)
+		}
+	}
+}
+
+func putPoint(buf *bytes.Buffer, p fixed.Point26_6) {
+	putVarint(buf, int32(p.X))
+	putVarint(buf, int32(p.Y))
+}
+
+// DecodeIconVG parses data as an IconVG stream and reconstructs it as an
+// *svgicon.SvgIcon: one svgicon.SvgPath per styling opcode, its Path
+// rebuilt from the drawing opcodes that follow.
+func DecodeIconVG(data []byte) (*svgicon.SvgIcon, error) {
+	if len(data) < 4 || [4]byte{data[0], data[1], data[2], data[3]} != magic {
+		return nil, errors.New("iconvg: bad magic header")
+	}
+	data = data[4:]
+
+	nChunks, n, err := getVarint(data)
+	if err != nil {
+		return nil, err
+	}
+	data = data[n:]
+
+	icon := &svgicon.SvgIcon{Transform: svgicon.Identity, AspectRatio: svgicon.DefaultPreserveAspectRatio}
+	for i := int32(0); i < nChunks; i++ {
+		mid, n, err := getVarint(data)
+		if err != nil {
+			return nil, err
+		}
+		data = data[n:]
+		if mid != midViewBox {
+			return nil, fmt.Errorf("iconvg: unsupported metadata chunk MID=%d", mid)
+		}
+		var coords [4]int32
+		for j := range coords {
+			coords[j], n, err = getVarint(data)
+			if err != nil {
+				return nil, err
+			}
+			data = data[n:]
+		}
+		icon.ViewBox.X, icon.ViewBox.Y = float64(coords[0]), float64(coords[1])
+		icon.ViewBox.W, icon.ViewBox.H = float64(coords[2]-coords[0]), float64(coords[3]-coords[1])
+	}
+
+	var (
+		cur  svgicon.Path
+		style svgicon.PathStyle
+		have bool
+	)
+	flush := func() {
+		if have {
+			icon.SVGPaths = append(icon.SVGPaths, svgicon.SvgPath{PathStyle: style, Path: cur})
+		}
+	}
+	pos := fixed.Point26_6{}
+	for len(data) > 0 {
+		op := data[0]
+		data = data[1:]
+		switch {
+		case op == opSetCReg:
+			flush()
+			style = svgicon.DefaultStyle
+			data = data[1:] // register index, unused: paths are processed in order
+			c, rest, err := readRGBA(data)
+			if err != nil {
+				return nil, err
+			}
+			style.FillerColor = svgicon.PlainColor(c)
+			data = rest
+			cur, have = nil, true
+		case op == opSelectGradient:
+			flush()
+			style = svgicon.DefaultStyle
+			data = data[1:] // register index
+			grad, rest, err := readGradient(data)
+			if err != nil {
+				return nil, err
+			}
+			style.FillerColor = grad
+			data = rest
+			cur, have = nil, true
+		case op == opNoFill:
+			flush()
+			style = svgicon.DefaultStyle
+			style.FillerColor = nil
+			data = data[1:] // register index, unused: paths are processed in order
+			cur, have = nil, true
+		case op == opMoveTo:
+			p, rest, err := readPoint(data)
+			if err != nil {
+				return nil, err
+			}
+			pos, data = p, rest
+			cur = append(cur, svgicon.OpMoveTo(pos))
+		case op == opLineTo:
+			p, rest, err := readPoint(data)
+			if err != nil {
+				return nil, err
+			}
+			pos, data = p, rest
+			cur = append(cur, svgicon.OpLineTo(pos))
+		case op == opQuadTo || op == opQuadToRel:
+			b, c2, rest, err := readTwoPoints(data)
+			if err != nil {
+				return nil, err
+			}
+			if op == opQuadToRel {
+				b, c2 = addPoint(b, pos), addPoint(c2, pos)
+			}
+			pos, data = c2, rest
+			cur = append(cur, svgicon.OpQuadTo{b, c2})
+		case op == opCubicTo || op == opCubicToRel:
+			b, c2, d, rest, err := readThreePoints(data)
+			if err != nil {
+				return nil, err
+			}
+			if op == opCubicToRel {
+				b, c2, d = addPoint(b, pos), addPoint(c2, pos), addPoint(d, pos)
+			}
+			pos, data = d, rest
+			cur = append(cur, svgicon.OpCubicTo{b, c2, d})
+		case op == opArcTo:
+			// Path has no native arc operation, and this opcode carries
+			// only the end point: it is drawn as a cubic Bezier whose
+			// control points sit on its ends, i.e. a straight segment.
+			end, rest, err := readPoint(data)
+			if err != nil {
+				return nil, err
+			}
+			data = rest
+			c1, c2 := pos, end
+			cur = append(cur, svgicon.OpCubicTo{c1, c2, end})
+			pos = end
+		case op == opClose:
+			cur = append(cur, svgicon.OpClose{})
+		default:
+			return nil, fmt.Errorf("iconvg: unknown opcode 0x%02x", op)
+		}
+	}
+	flush()
+
+	return icon, nil
+}
+
+// Decode parses data as an IconVG stream, like DecodeIconVG, then draws
+// the result straight to drv at the given opacity without the caller
+// having to hold onto the intermediate *svgicon.SvgIcon.
+func Decode(data []byte, drv svgicon.Driver, opacity float64) error {
+	icon, err := DecodeIconVG(data)
+	if err != nil {
+		return err
+	}
+	icon.Draw(drv, opacity)
+	return nil
+}
+
+func addPoint(p, by fixed.Point26_6) fixed.Point26_6 {
+	return fixed.Point26_6{X: p.X + by.X, Y: p.Y + by.Y}
+}
+
+func readPoint(data []byte) (fixed.Point26_6, []byte, error) {
+	x, n, err := getVarint(data)
+	if err != nil {
+		return fixed.Point26_6{}, nil, err
+	}
+	data = data[n:]
+	y, n, err := getVarint(data)
+	if err != nil {
+		return fixed.Point26_6{}, nil, err
+	}
+	return fixed.Point26_6{X: fixed.Int26_6(x), Y: fixed.Int26_6(y)}, data[n:], nil
+}
+
+func readTwoPoints(data []byte) (a, b fixed.Point26_6, rest []byte, err error) {
+	a, data, err = readPoint(data)
+	if err != nil {
+		return
+	}
+	b, data, err = readPoint(data)
+	return a, b, data, err
+}
+
+func readThreePoints(data []byte) (a, b, c fixed.Point26_6, rest []byte, err error) {
+	a, b, data, err = readTwoPoints(data)
+	if err != nil {
+		return
+	}
+	c, data, err = readPoint(data)
+	return a, b, c, data, err
+}
+
+func readRGBA(data []byte) (color.NRGBA, []byte, error) {
+	if len(data) < 4 {
+		return color.NRGBA{}, nil, errors.New("iconvg: truncated color")
+	}
+	return color.NRGBA{R: data[0], G: data[1], B: data[2], A: data[3]}, data[4:], nil
+}
+
+func readGradient(data []byte) (svgicon.Gradient, []byte, error) {
+	if len(data) < 1 {
+		return svgicon.Gradient{}, nil, errors.New("iconvg: truncated gradient")
+	}
+	isRadial := data[0] == 1
+	data = data[1:]
+	nStops, n, err := getVarint(data)
+	if err != nil {
+		return svgicon.Gradient{}, nil, err
+	}
+	data = data[n:]
+
+	grad := svgicon.Gradient{Units: svgicon.ObjectBoundingBox}
+	if isRadial {
+		grad.Direction = svgicon.Radial{0.5, 0.5, 0.5, 0.5, 0.5, 0}
+	} else {
+		grad.Direction = svgicon.Linear{0, 0, 1, 0}
+	}
+	stops := make([]svgicon.GradStop, nStops)
+	for i := range stops {
+		off, n, err := getVarint(data)
+		if err != nil {
+			return svgicon.Gradient{}, nil, err
+		}
+		data = data[n:]
+		c, rest, err := readRGBA(data)
+		if err != nil {
+			return svgicon.Gradient{}, nil, err
+		}
+		data = rest
+		stops[i] = svgicon.GradStop{Offset: float64(off) / (1 << 14), StopColor: c, Opacity: float64(c.A) / 0xff}
+	}
+	grad.Stops = stops
+	return grad, data, nil
+}
@@ -0,0 +1,66 @@
+package iconvg
+
+import (
+	"bytes"
+	"errors"
+)
+
+// putNumber and getVarint implement IconVG's variable-length number
+// encoding: a value is zig-zag encoded to an unsigned magnitude, then
+// packed into 1, 2 or 4 bytes, with the low bits of the first byte
+// discriminating the width (0 = 1 byte, 1 = 2 bytes, 3 = 4 bytes). This
+// keeps the common case of small, icon-sized coordinates (roughly ±64
+// units) to a single byte.
+func putVarint(buf *bytes.Buffer, v int32) {
+	zz := zigzag(v)
+	switch {
+	case zz < 1<<7:
+		buf.WriteByte(byte(zz << 1))
+	case zz < 1<<14:
+		u := (zz << 2) | 0x01
+		buf.WriteByte(byte(u))
+		buf.WriteByte(byte(u >> 8))
+	default:
+		u := (zz << 2) | 0x03
+		buf.WriteByte(byte(u))
+		buf.WriteByte(byte(u >> 8))
+		buf.WriteByte(byte(u >> 16))
+		buf.WriteByte(byte(u >> 24))
+	}
+}
+
+// putNumber is an alias of putVarint kept separate so call sites that
+// encode geometric quantities (as opposed to counts or ids) read clearly.
+func putNumber(buf *bytes.Buffer, v float64) {
+	putVarint(buf, int32(v))
+}
+
+func getVarint(data []byte) (v int32, consumed int, err error) {
+	if len(data) == 0 {
+		return 0, 0, errors.New("iconvg: truncated number")
+	}
+	b0 := data[0]
+	if b0&0x01 == 0 {
+		return unzigzag(uint32(b0) >> 1), 1, nil
+	}
+	if len(data) < 2 {
+		return 0, 0, errors.New("iconvg: truncated number")
+	}
+	u16 := uint32(data[0]) | uint32(data[1])<<8
+	if u16&0x02 == 0 {
+		return unzigzag(u16 >> 2), 2, nil
+	}
+	if len(data) < 4 {
+		return 0, 0, errors.New("iconvg: truncated number")
+	}
+	u32 := uint32(data[0]) | uint32(data[1])<<8 | uint32(data[2])<<16 | uint32(data[3])<<24
+	return unzigzag(u32 >> 2), 4, nil
+}
+
+func zigzag(v int32) uint32 {
+	return uint32((v << 1) ^ (v >> 31))
+}
+
+func unzigzag(zz uint32) int32 {
+	return int32(zz>>1) ^ -int32(zz&1)
+}
@@ -13,7 +13,7 @@ import (
 // is enough to draw many icons. errMode determines if the icon ignores, errors out, or logs a warning
 // if it does not handle an element found in the icon file.
 func ReadIconStream(stream io.Reader, errMode ErrorMode) (*SvgIcon, error) {
-	icon := &SvgIcon{defs: make(map[string][]definition), grads: make(map[string]*Gradient), Transform: Identity}
+	icon := &SvgIcon{defs: make(map[string][]definition), grads: make(map[string]*Gradient), Transform: Identity, AspectRatio: DefaultPreserveAspectRatio}
 	cursor := &iconCursor{styleStack: []PathStyle{DefaultStyle}, icon: icon}
 	cursor.errorMode = errMode
 	decoder := xml.NewDecoder(stream)
@@ -39,6 +39,14 @@ func ReadIconStream(stream io.Reader, errMode ErrorMode) (*SvgIcon, error) {
 			if err != nil {
 				return icon, err
 			}
+			if se.Name.Local == "svg" {
+				if par := attrValue(se.Attr, "preserveAspectRatio"); par != "" {
+					icon.AspectRatio, err = ParsePreserveAspectRatio(par)
+					if err != nil {
+						return icon, err
+					}
+				}
+			}
 		case xml.EndElement:
 			// pop style
 			cursor.styleStack = cursor.styleStack[:len(cursor.styleStack)-1]
@@ -74,6 +82,17 @@ func ReadIconStream(stream io.Reader, errMode ErrorMode) (*SvgIcon, error) {
 	return icon, nil
 }
 
+// attrValue returns the value of the attribute named key, or "" if attrs
+// has no such attribute.
+func attrValue(attrs []xml.Attr, key string) string {
+	for _, attr := range attrs {
+		if attr.Name.Local == key {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
 // ReadIcon reads the Icon from the named file
 // This only supports a sub-set of SVG, but
 // is enough to draw many icons. errMode determines if the icon ignores, errors out, or logs a warning
@@ -0,0 +1,96 @@
+package svgraster
+
+import (
+	"image"
+	"io"
+
+	"github.com/inkeliz/oksvg/svgicon"
+	"github.com/srwiley/rasterx"
+)
+
+// Fit picks how an icon's ViewBox is scaled to cover a target rectangle
+// of a different aspect ratio, mirroring SVG's preserveAspectRatio
+// meet/slice keywords.
+type Fit uint8
+
+const (
+	// FitMeet scales the ViewBox down to fit entirely inside the target,
+	// leaving blank space on one axis. This is SVG's default.
+	FitMeet Fit = iota
+	// FitSlice scales the ViewBox up to cover the target entirely,
+	// cropping whatever overflows on one axis.
+	FitSlice
+	// FitStretch ignores the aspect ratio and maps the ViewBox directly
+	// onto the target.
+	FitStretch
+)
+
+// AspectFit configures RasterSVGIconToImageFit.
+type AspectFit struct {
+	Fit   Fit
+	Align svgicon.Align
+}
+
+// RasterSVGIconToImageFit behaves like RasterSVGIconToImageSize, but
+// instead of distorting the icon to exactly fill width x height, it
+// scales and aligns the icon's ViewBox inside that rectangle according
+// to fit, the same way a browser lays out an <img> with
+// preserveAspectRatio. If fit is nil, the icon's own
+// preserveAspectRatio attribute (or "xMidYMid meet" if it has none) is
+// used instead.
+func RasterSVGIconToImageFit(icon io.Reader, scanner rasterx.Scanner, width, height int, fit *AspectFit) (*image.RGBA, error) {
+	return rasterSVG(icon, scanner, width, height, fit)
+}
+
+func aspectFitFromDocument(par svgicon.PreserveAspectRatio) AspectFit {
+	fit := FitMeet
+	if par.MeetOrSlice == svgicon.Slice {
+		fit = FitSlice
+	}
+	if par.Align == svgicon.AlignNone {
+		return AspectFit{Fit: FitStretch}
+	}
+	return AspectFit{Fit: fit, Align: par.Align}
+}
+
+// fitRect returns the target rectangle (in the coordinates SetTarget
+// expects) that places icon's ViewBox inside a width x height canvas
+// according to fit.
+func fitRect(icon *svgicon.SvgIcon, width, height int, fit AspectFit) (x, y, w, h float64) {
+	if fit.Fit == FitStretch || icon.ViewBox.W == 0 || icon.ViewBox.H == 0 {
+		return 0, 0, float64(width), float64(height)
+	}
+
+	scaleX := float64(width) / icon.ViewBox.W
+	scaleY := float64(height) / icon.ViewBox.H
+	scale := scaleX
+	switch {
+	case fit.Fit == FitMeet && scaleY < scaleX:
+		scale = scaleY
+	case fit.Fit == FitSlice && scaleY > scaleX:
+		scale = scaleY
+	}
+
+	w = icon.ViewBox.W * scale
+	h = icon.ViewBox.H * scale
+	x, y = alignOffset(fit.Align, width, height, w, h)
+	return x, y, w, h
+}
+
+// alignOffset returns the top-left corner at which a w x h rectangle
+// should be placed inside a cw x ch canvas for the given alignment.
+func alignOffset(align svgicon.Align, cw, ch int, w, h float64) (x, y float64) {
+	switch align {
+	case svgicon.AlignXMidYMin, svgicon.AlignXMidYMid, svgicon.AlignXMidYMax:
+		x = (float64(cw) - w) / 2
+	case svgicon.AlignXMaxYMin, svgicon.AlignXMaxYMid, svgicon.AlignXMaxYMax:
+		x = float64(cw) - w
+	}
+	switch align {
+	case svgicon.AlignXMinYMid, svgicon.AlignXMidYMid, svgicon.AlignXMaxYMid:
+		y = (float64(ch) - h) / 2
+	case svgicon.AlignXMinYMax, svgicon.AlignXMidYMax, svgicon.AlignXMaxYMax:
+		y = float64(ch) - h
+	}
+	return x, y
+}
@@ -0,0 +1,178 @@
+package svgraster
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"image"
+	"image/draw"
+	"math"
+
+	"github.com/inkeliz/oksvg/svgicon"
+	"github.com/srwiley/rasterx"
+)
+
+// GlyphOption configures RasterGlyphSVG.
+type GlyphOption func(*glyphOptions)
+
+type glyphOptions struct {
+	elementID string
+}
+
+// WithGlyphElementID restricts rasterization to the subtree of source
+// rooted at the element whose id attribute equals id, e.g. "glyph123" for
+// an OpenType `SVG ` table document that bundles every glyph under a
+// single root as sibling <g id="glyphNNN"> elements. Without this
+// option, the whole document is rasterized as-is.
+func WithGlyphElementID(id string) GlyphOption {
+	return func(o *glyphOptions) { o.elementID = id }
+}
+
+// ParseGlyphSVG parses an OpenType `SVG ` table glyph document,
+// narrowing it to a single glyph's subtree first if WithGlyphElementID
+// is given. Callers that repeatedly draw the same glyph (e.g. once per
+// line of text) should call this once and reuse the returned
+// *svgicon.SvgIcon with RasterParsedGlyphSVG, rather than reparse source
+// on every draw via RasterGlyphSVG.
+func ParseGlyphSVG(source []byte, opts ...GlyphOption) (*svgicon.SvgIcon, error) {
+	var options glyphOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	doc := source
+	if options.elementID != "" {
+		var err error
+		doc, err = extractGlyphElement(source, options.elementID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return svgicon.ReadIconStream(bytes.NewReader(doc), svgicon.WarnErrorMode)
+}
+
+// RasterGlyphSVG rasterizes an OpenType `SVG ` table glyph document into
+// an image sized pixWidth x pixHeight, returning the placement rectangle
+// a text shaper should draw.Draw it at, given the glyph's bearings in
+// the same pixels-per-em space as pixWidth/pixHeight. xBearing/yBearing
+// follow the font convention of being measured from the pen position to
+// the glyph's origin, with y increasing upward; the returned Rectangle
+// is in image space, where y increases downward.
+//
+// Callers that repeatedly draw the same glyph should use ParseGlyphSVG
+// and RasterParsedGlyphSVG instead, since RasterGlyphSVG always
+// reparses source.
+func RasterGlyphSVG(source []byte, pixWidth, pixHeight int, xBearing, yBearing float32, opts ...GlyphOption) (*image.NRGBA, image.Rectangle, error) {
+	icon, err := ParseGlyphSVG(source, opts...)
+	if err != nil {
+		return nil, image.Rectangle{}, err
+	}
+
+	return RasterParsedGlyphSVG(icon, pixWidth, pixHeight, xBearing, yBearing)
+}
+
+// RasterParsedGlyphSVG behaves like RasterGlyphSVG, but takes a glyph
+// already parsed by ParseGlyphSVG instead of reparsing raw source on
+// every call.
+func RasterParsedGlyphSVG(icon *svgicon.SvgIcon, pixWidth, pixHeight int, xBearing, yBearing float32) (*image.NRGBA, image.Rectangle, error) {
+	rgba, err := rasterSVGIcon(icon, nil, pixWidth, pixHeight)
+	if err != nil {
+		return nil, image.Rectangle{}, err
+	}
+
+	img := image.NewNRGBA(rgba.Bounds())
+	draw.Draw(img, img.Bounds(), rgba, image.Point{}, draw.Src)
+
+	origin := image.Pt(
+		int(math.Round(float64(xBearing))),
+		int(math.Round(float64(-yBearing))),
+	)
+	placement := image.Rectangle{Min: origin, Max: origin.Add(image.Pt(pixWidth, pixHeight))}
+
+	return img, placement, nil
+}
+
+func rasterSVGIcon(icon *svgicon.SvgIcon, scanner rasterx.Scanner, w, h int) (*image.RGBA, error) {
+	return rasterIcon(icon, scanner, w, h, nil), nil
+}
+
+// extractGlyphElement returns a standalone SVG document containing only
+// the subtree of source rooted at the element whose id attribute equals
+// id, reusing the root <svg>'s namespace and viewBox/width/height
+// attributes so the extracted glyph keeps its original coordinate space.
+func extractGlyphElement(source []byte, id string) ([]byte, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(source))
+
+	var rootAttrs []xml.Attr
+	var out bytes.Buffer
+	encoder := xml.NewEncoder(&out)
+
+	depth := -1 // depth of the matched element once found, -1 means "not found yet"
+	// wrapped records whether a synthetic <svg> root was opened around
+	// the match; a matched <svg> element is emitted as its own root.
+	wrapped := false
+	for {
+		tok, err := decoder.Token()
+		if err != nil {
+			break
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if rootAttrs == nil && t.Name.Local == "svg" {
+				rootAttrs = t.Attr
+			}
+			if depth < 0 && hasID(t.Attr, id) {
+				depth = 0
+				wrapped = t.Name.Local != "svg"
+				if wrapped {
+					start := xml.StartElement{Name: xml.Name{Local: "svg"}, Attr: rootAttrs}
+					if err := encoder.EncodeToken(start); err != nil {
+						return nil, err
+					}
+				}
+			}
+			if depth >= 0 {
+				if err := encoder.EncodeToken(t); err != nil {
+					return nil, err
+				}
+				depth++
+			}
+		case xml.EndElement:
+			if depth >= 0 {
+				depth--
+				if err := encoder.EncodeToken(t); err != nil {
+					return nil, err
+				}
+				if depth == 0 {
+					if wrapped {
+						if err := encoder.EncodeToken(xml.EndElement{Name: xml.Name{Local: "svg"}}); err != nil {
+							return nil, err
+						}
+					}
+					if err := encoder.Flush(); err != nil {
+						return nil, err
+					}
+					return out.Bytes(), nil
+				}
+			}
+		default:
+			if depth >= 0 {
+				if err := encoder.EncodeToken(tok); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return nil, errors.New("svgraster: no element with id " + id + " found in glyph document")
+}
+
+func hasID(attrs []xml.Attr, id string) bool {
+	for _, a := range attrs {
+		if a.Name.Local == "id" && a.Value == id {
+			return true
+		}
+	}
+	return false
+}
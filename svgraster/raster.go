@@ -1,5 +1,7 @@
 // Implements a raster backend to render SVG images,
-// by wrapping github.com/srwiley/rasterx.
+// by wrapping github.com/srwiley/rasterx. A second, dependency-free
+// backend built on golang.org/x/image/vector is available through
+// NewDriverWithBackend for callers who don't need full stroke parity.
 package svgraster
 
 import (
@@ -12,6 +14,7 @@ import (
 // assert interface conformance
 var (
 	_ svgicon.Driver  = Driver{}
+	_ svgicon.Backend = Driver{}
 	_ svgicon.Filler  = filler{}
 	_ svgicon.Stroker = stroker{}
 )
@@ -44,35 +47,47 @@ func (rd Driver) SetupDrawers(willFill, willStroke bool) (f svgicon.Filler, s sv
 	return f, s
 }
 
+// Unsupported always returns nil: the rasterx backend has full parity
+// for every stroke join, cap, gap and gradient oksvg supports.
+func (rd Driver) Unsupported() error { return nil }
+
 // RasterSVGIconToImage uses a scanner instance to renderer the
 // icon into an image and return it.
 // If `scanner` is nil, a default scanner rasterx.ScannerGV is used.
 func RasterSVGIconToImage(icon io.Reader, scanner rasterx.Scanner) (*image.RGBA, error) {
-	return rasterSVG(icon, scanner, 0, 0)
+	return rasterSVG(icon, scanner, 0, 0, nil)
 }
 
 // RasterSVGIconToImageSize uses a scanner instance to render the icon
-// with a custom size, instead of the original size of the SVG file.
+// with a custom size, instead of the original size of the SVG file. The
+// icon's ViewBox is scaled and aligned inside width x height according
+// to its own preserveAspectRatio attribute (or "xMidYMid meet" if it has
+// none); use RasterSVGIconToImageFit to override that with an explicit
+// AspectFit.
 // If `scanner` is nil, a default scanner rasterx.ScannerGV is used.
 func RasterSVGIconToImageSize(icon io.Reader, scanner rasterx.Scanner, width, height int) (*image.RGBA, error) {
-	return rasterSVG(icon, scanner, width, height)
+	return rasterSVG(icon, scanner, width, height, nil)
 }
 
 // RasterIcon uses a sacanner instance to render the icon
 // with a custom size, instead of the original size of the SVG file.
 // If `scanner` is nil, a default scanner rasterx.ScannerGV is used.
 func RasterIcon(icon *svgicon.SvgIcon) (*image.RGBA, error) {
-	return rasterIcon(icon, nil, int(icon.ViewBox.W), int(icon.ViewBox.H)), nil
+	return rasterIcon(icon, nil, int(icon.ViewBox.W), int(icon.ViewBox.H), nil), nil
 }
 
-// RasterIconSize uses a sacanner instance to render the icon
-// with a custom size, instead of the original size of the SVG file.
+// RasterIconSize uses a sacanner instance to render the icon with a
+// custom size, instead of the original size of the SVG file. The icon's
+// ViewBox is scaled and aligned inside width x height according to its
+// own preserveAspectRatio attribute (or "xMidYMid meet" if it has none);
+// use RasterSVGIconToImageFit to override that with an explicit
+// AspectFit.
 // If `scanner` is nil, a default scanner rasterx.ScannerGV is used.
 func RasterIconSize(icon *svgicon.SvgIcon, width, height int) (*image.RGBA, error) {
-	return rasterIcon(icon, nil, width, height), nil
+	return rasterIcon(icon, nil, width, height, nil), nil
 }
 
-func rasterSVG(icon io.Reader, scanner rasterx.Scanner, w, h int) (*image.RGBA, error) {
+func rasterSVG(icon io.Reader, scanner rasterx.Scanner, w, h int, fit *AspectFit) (*image.RGBA, error) {
 	parsedIcon, err := svgicon.ReadIconStream(icon, svgicon.WarnErrorMode)
 	if err != nil {
 		return nil, err
@@ -82,11 +97,23 @@ func rasterSVG(icon io.Reader, scanner rasterx.Scanner, w, h int) (*image.RGBA,
 		w, h = int(parsedIcon.ViewBox.W), int(parsedIcon.ViewBox.H)
 	}
 
-	return rasterIcon(parsedIcon, scanner, w, h), nil
+	return rasterIcon(parsedIcon, scanner, w, h, fit), nil
 }
 
-func rasterIcon(icon *svgicon.SvgIcon, scanner rasterx.Scanner, w, h int) *image.RGBA {
-	icon.SetTarget(0, 0, float64(w), float64(h))
+// rasterIcon renders icon into a w x h image, placing its ViewBox inside
+// that canvas according to fit. If fit is nil, the icon's own
+// preserveAspectRatio attribute (or "xMidYMid meet" if it has none) is
+// used instead, so every entry point in this package honors the
+// document's preference by default unless the caller overrides it via
+// RasterSVGIconToImageFit.
+func rasterIcon(icon *svgicon.SvgIcon, scanner rasterx.Scanner, w, h int, fit *AspectFit) *image.RGBA {
+	resolved := fit
+	if resolved == nil {
+		f := aspectFitFromDocument(icon.AspectRatio)
+		resolved = &f
+	}
+	x, y, fw, fh := fitRect(icon, w, h, *resolved)
+	icon.SetTarget(x, y, fw, fh)
 
 	img := image.NewRGBA(image.Rect(0, 0, w, h))
 
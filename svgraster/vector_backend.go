@@ -0,0 +1,411 @@
+package svgraster
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/inkeliz/oksvg/svgicon"
+	"github.com/srwiley/rasterx"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+// ErrUnsupported is recorded by VectorDriver (see Unsupported) when asked to
+// stroke with a join, cap or dash pattern the pure-Go vector.Rasterizer
+// backend has no equivalent for; that path's whole stroke is skipped
+// rather than drawn incorrectly.
+var ErrUnsupported = errors.New("svgraster: stroke style not supported by the vector backend")
+
+// RasterizerBackend selects which rasterizer implementation
+// NewDriverWithBackend builds.
+type RasterizerBackend int
+
+const (
+	// RasterXBackend wraps github.com/srwiley/rasterx, with full parity
+	// for every stroke join/cap/gap oksvg supports. This is what
+	// NewDriver always uses.
+	RasterXBackend RasterizerBackend = iota
+	// VectorBackend wraps golang.org/x/image/vector.Rasterizer, a
+	// dependency-free pure-Go scanline rasterizer. It fills shapes
+	// exactly, but only supports simple (round-joined, non-dashed)
+	// strokes; anything else is reported via VectorDriver.Unsupported and
+	// skipped.
+	VectorBackend
+)
+
+// NewDriverWithBackend returns a svgicon.Backend rendering into an
+// image of the given size, built from the chosen RasterizerBackend.
+// scanner is only used by RasterXBackend (as in NewDriver); it is
+// ignored, and may be nil, for VectorBackend.
+func NewDriverWithBackend(width, height int, scanner rasterx.Scanner, backend RasterizerBackend) svgicon.Backend {
+	if backend == VectorBackend {
+		return newVectorDriver(width, height)
+	}
+	return NewDriver(width, height, scanner)
+}
+
+// VectorDriver is the golang.org/x/image/vector-backed implementation
+// of svgicon.Backend.
+type VectorDriver struct {
+	img *image.RGBA
+	err error
+}
+
+var _ svgicon.Backend = (*VectorDriver)(nil)
+
+func newVectorDriver(width, height int) *VectorDriver {
+	return &VectorDriver{img: image.NewRGBA(image.Rect(0, 0, width, height))}
+}
+
+// Image returns the target VectorDriver is rendering into.
+func (d *VectorDriver) Image() *image.RGBA { return d.img }
+
+// Unsupported returns the first ErrUnsupported encountered while
+// drawing a stroke with this VectorDriver, if any. Gradient fills and
+// strokes, which this backend draws transparent, are not reported here.
+func (d *VectorDriver) Unsupported() error { return d.err }
+
+func (d *VectorDriver) SetupDrawers(willFill, willStroke bool) (svgicon.Filler, svgicon.Stroker) {
+	b := d.img.Bounds()
+	var f svgicon.Filler
+	var s svgicon.Stroker
+	if willFill {
+		f = &vectorFiller{vectorPath: newVectorPath(b), target: d.img}
+	}
+	if willStroke {
+		s = &vectorStroker{vectorPath: newVectorPath(b), target: d.img, driver: d}
+	}
+	return f, s
+}
+
+// vectorPath accumulates a Path's drawTo calls both into a
+// vector.Rasterizer (used directly for fills) and, flattened to
+// polylines, for the stroker's own outline construction.
+type vectorPath struct {
+	raster   *vector.Rasterizer
+	bounds   image.Rectangle
+	subpaths [][]fixed.Point26_6
+	cur      fixed.Point26_6
+}
+
+func newVectorPath(bounds image.Rectangle) vectorPath {
+	return vectorPath{
+		raster: vector.NewRasterizer(bounds.Dx(), bounds.Dy()),
+		bounds: bounds,
+	}
+}
+
+func (p *vectorPath) Start(a fixed.Point26_6) {
+	x, y := toFloat32(a)
+	p.raster.MoveTo(x, y)
+	p.subpaths = append(p.subpaths, []fixed.Point26_6{a})
+	p.cur = a
+}
+
+func (p *vectorPath) Line(b fixed.Point26_6) {
+	x, y := toFloat32(b)
+	p.raster.LineTo(x, y)
+	p.appendPoint(b)
+}
+
+func (p *vectorPath) QuadBezier(b, c fixed.Point26_6) {
+	x1, y1 := toFloat32(b)
+	x2, y2 := toFloat32(c)
+	p.raster.QuadTo(x1, y1, x2, y2)
+	p.appendFlattenedQuad(b, c)
+}
+
+func (p *vectorPath) CubeBezier(b, c, d fixed.Point26_6) {
+	x1, y1 := toFloat32(b)
+	x2, y2 := toFloat32(c)
+	x3, y3 := toFloat32(d)
+	p.raster.CubeTo(x1, y1, x2, y2, x3, y3)
+	p.appendFlattenedCubic(b, c, d)
+}
+
+func (p *vectorPath) Stop(closeLoop bool) {
+	if !closeLoop {
+		return
+	}
+	p.raster.ClosePath()
+	if n := len(p.subpaths); n > 0 && len(p.subpaths[n-1]) > 0 {
+		p.subpaths[n-1] = append(p.subpaths[n-1], p.subpaths[n-1][0])
+	}
+}
+
+func (p *vectorPath) appendPoint(pt fixed.Point26_6) {
+	if n := len(p.subpaths); n > 0 {
+		p.subpaths[n-1] = append(p.subpaths[n-1], pt)
+	}
+	p.cur = pt
+}
+
+// curveFlattenSteps is how many line segments approximate a quadratic
+// or cubic Bezier when building the polyline the stroker offsets; it is
+// not used for fills, which the vector.Rasterizer flattens itself.
+const curveFlattenSteps = 8
+
+func (p *vectorPath) appendFlattenedQuad(b, c fixed.Point26_6) {
+	a := p.cur
+	for i := 1; i <= curveFlattenSteps; i++ {
+		t := float64(i) / curveFlattenSteps
+		p.appendPoint(lerpQuad(a, b, c, t))
+	}
+}
+
+func (p *vectorPath) appendFlattenedCubic(b, c, d fixed.Point26_6) {
+	a := p.cur
+	for i := 1; i <= curveFlattenSteps; i++ {
+		t := float64(i) / curveFlattenSteps
+		p.appendPoint(lerpCubic(a, b, c, d, t))
+	}
+}
+
+func toFloat32(p fixed.Point26_6) (float32, float32) {
+	return float32(p.X) / 64, float32(p.Y) / 64
+}
+
+func f64(v fixed.Int26_6) float64 { return float64(v) }
+
+func lerpQuad(a, b, c fixed.Point26_6, t float64) fixed.Point26_6 {
+	u := 1 - t
+	x := u*u*f64(a.X) + 2*u*t*f64(b.X) + t*t*f64(c.X)
+	y := u*u*f64(a.Y) + 2*u*t*f64(b.Y) + t*t*f64(c.Y)
+	return fixed.Point26_6{X: fixed.Int26_6(x), Y: fixed.Int26_6(y)}
+}
+
+func lerpCubic(a, b, c, d fixed.Point26_6, t float64) fixed.Point26_6 {
+	u := 1 - t
+	x := u*u*u*f64(a.X) + 3*u*u*t*f64(b.X) + 3*u*t*t*f64(c.X) + t*t*t*f64(d.X)
+	y := u*u*u*f64(a.Y) + 3*u*u*t*f64(b.Y) + 3*u*t*t*f64(c.Y) + t*t*t*f64(d.Y)
+	return fixed.Point26_6{X: fixed.Int26_6(x), Y: fixed.Int26_6(y)}
+}
+
+// vectorFiller fills paths using the vector.Rasterizer directly: no
+// join/cap logic is needed for a fill, so plain-color fills match the
+// rasterx backend. Gradient fills are drawn transparent (see
+// patternImage).
+type vectorFiller struct {
+	vectorPath
+	target *image.RGBA
+}
+
+func (f *vectorFiller) Draw(c svgicon.Pattern, opacity float64) {
+	f.raster.Draw(f.target, f.target.Bounds(), patternImage(c, opacity), image.Point{})
+}
+
+// vectorStroker only supports round joins with butt, round or square
+// caps (independently at each end) and no dashing; anything else is
+// reported through the owning VectorDriver's Unsupported method and
+// that path's stroke is left undrawn.
+type vectorStroker struct {
+	vectorPath
+	target  *image.RGBA
+	driver  *VectorDriver
+	options svgicon.StrokeOptions
+}
+
+func (s *vectorStroker) SetStrokeOptions(options svgicon.StrokeOptions) {
+	s.options = options
+}
+
+// capKind is this backend's own classification of a svgicon.LineCap,
+// used to pick what geometry strokePolyline adds at an open end.
+type capKind int
+
+const (
+	capButt capKind = iota
+	capRound
+	capSquare
+)
+
+func (s *vectorStroker) Draw(c svgicon.Pattern, opacity float64) {
+	var leadCap, trailCap capKind
+	var leadOK, trailOK bool
+	switch s.options.Join.LeadLineCap {
+	case svgicon.ButtCap:
+		leadCap, leadOK = capButt, true
+	case svgicon.RoundCap:
+		leadCap, leadOK = capRound, true
+	case svgicon.SquareCap:
+		leadCap, leadOK = capSquare, true
+	}
+	switch s.options.Join.TrailLineCap {
+	case svgicon.ButtCap:
+		trailCap, trailOK = capButt, true
+	case svgicon.RoundCap:
+		trailCap, trailOK = capRound, true
+	case svgicon.SquareCap:
+		trailCap, trailOK = capSquare, true
+	}
+	if len(s.options.Dash.Dash) > 0 || s.options.Join.LineJoin != svgicon.Round || !leadOK || !trailOK {
+		s.driver.err = ErrUnsupported
+		return
+	}
+
+	outline := vector.NewRasterizer(s.bounds.Dx(), s.bounds.Dy())
+	half := s.options.LineWidth / 2
+	for _, poly := range s.subpaths {
+		strokePolyline(outline, poly, half, leadCap, trailCap)
+	}
+	outline.Draw(s.target, s.target.Bounds(), patternImage(c, opacity), image.Point{})
+}
+
+// strokePolyline emits into outline the union of one filled
+// quadrilateral per segment of poly, plus a round join disc at every
+// interior vertex and, for an open polyline, whatever cap geometry
+// leadCap/trailCap call for at poly's start/end, so consecutive
+// segments meet smoothly instead of leaving a gap or a visible chamfer
+// at each corner. Each piece is its own closed subpath; emitClosedSubpath
+// normalizes their winding so overlapping pieces union under the
+// rasterizer's nonzero fill rule rather than cancel out.
+func strokePolyline(outline *vector.Rasterizer, poly []fixed.Point26_6, half float64, leadCap, trailCap capKind) {
+	if len(poly) < 2 || half <= 0 {
+		return
+	}
+	closed := poly[0] == poly[len(poly)-1]
+
+	for i := 0; i+1 < len(poly); i++ {
+		nx, ny := segmentNormal(poly[i], poly[i+1], half)
+		emitClosedSubpath(outline, []fixed.Point26_6{
+			offset(poly[i], nx, ny), offset(poly[i+1], nx, ny),
+			offset(poly[i+1], -nx, -ny), offset(poly[i], -nx, -ny),
+		})
+	}
+
+	for i := 1; i+1 < len(poly); i++ {
+		emitClosedSubpath(outline, roundJoinDisc(poly[i], half))
+	}
+	if closed && len(poly) > 2 {
+		emitClosedSubpath(outline, roundJoinDisc(poly[0], half))
+	}
+	if !closed {
+		emitCap(outline, leadCap, poly[0], poly[1], half)
+		emitCap(outline, trailCap, poly[len(poly)-1], poly[len(poly)-2], half)
+	}
+}
+
+// emitCap draws kind's cap geometry beyond tip, facing away from the
+// segment coming from "from"; capButt needs no extra geometry since the
+// segment quad's own end is already flat.
+func emitCap(outline *vector.Rasterizer, kind capKind, tip, from fixed.Point26_6, half float64) {
+	switch kind {
+	case capRound:
+		emitClosedSubpath(outline, arcCap(tip, from, half))
+	case capSquare:
+		emitClosedSubpath(outline, squareCap(tip, from, half))
+	}
+}
+
+func segmentNormal(a, b fixed.Point26_6, half float64) (nx, ny float64) {
+	dx, dy := f64(b.X-a.X), f64(b.Y-a.Y)
+	length := math.Hypot(dx, dy)
+	if length == 0 {
+		return 0, 0
+	}
+	return -dy / length * half * 64, dx / length * half * 64
+}
+
+func offset(p fixed.Point26_6, dx, dy float64) fixed.Point26_6 {
+	return fixed.Point26_6{X: p.X + fixed.Int26_6(dx), Y: p.Y + fixed.Int26_6(dy)}
+}
+
+// roundJoinDisc returns a full circle of radius half around center, used
+// to fill the wedge a round join leaves between two adjoining segment
+// quads.
+func roundJoinDisc(center fixed.Point26_6, half float64) []fixed.Point26_6 {
+	const steps = 12
+	pts := make([]fixed.Point26_6, steps)
+	for i := range pts {
+		a := 2 * math.Pi * float64(i) / steps
+		pts[i] = fixed.Point26_6{
+			X: center.X + fixed.Int26_6(math.Cos(a)*half*64),
+			Y: center.Y + fixed.Int26_6(math.Sin(a)*half*64),
+		}
+	}
+	return pts
+}
+
+// arcCap approximates a round cap at tip, facing away from the segment
+// coming from "from", as a half-disc: a semicircular fan whose ends
+// emitClosedSubpath joins with a straight chord along the stroke's edge.
+func arcCap(tip, from fixed.Point26_6, half float64) []fixed.Point26_6 {
+	dx, dy := f64(tip.X-from.X), f64(tip.Y-from.Y)
+	baseAngle := math.Atan2(dy, dx)
+	const steps = 6
+	pts := make([]fixed.Point26_6, 0, steps+1)
+	for i := 0; i <= steps; i++ {
+		a := baseAngle - math.Pi/2 + math.Pi*float64(i)/steps
+		pts = append(pts, fixed.Point26_6{
+			X: tip.X + fixed.Int26_6(math.Cos(a)*half*64),
+			Y: tip.Y + fixed.Int26_6(math.Sin(a)*half*64),
+		})
+	}
+	return pts
+}
+
+// squareCap approximates a square cap at tip, facing away from the
+// segment coming from "from", as the rectangle extending the stroke's
+// width half further out past tip.
+func squareCap(tip, from fixed.Point26_6, half float64) []fixed.Point26_6 {
+	dx, dy := f64(tip.X-from.X), f64(tip.Y-from.Y)
+	length := math.Hypot(dx, dy)
+	if length == 0 {
+		return nil
+	}
+	ux, uy := dx/length, dy/length
+	nx, ny := -uy*half*64, ux*half*64
+	ext := fixed.Point26_6{X: tip.X + fixed.Int26_6(ux*half*64), Y: tip.Y + fixed.Int26_6(uy*half*64)}
+	return []fixed.Point26_6{
+		offset(tip, nx, ny), offset(ext, nx, ny),
+		offset(ext, -nx, -ny), offset(tip, -nx, -ny),
+	}
+}
+
+// emitClosedSubpath draws pts as a closed subpath of outline, reversing
+// them first if needed so every subpath this backend emits winds the
+// same way: vector.Rasterizer fills with the nonzero rule, so pieces
+// with consistent winding union where they overlap, while mismatched
+// winding would cancel out and punch holes instead.
+func emitClosedSubpath(outline *vector.Rasterizer, pts []fixed.Point26_6) {
+	if len(pts) < 3 {
+		return
+	}
+	if signedArea(pts) < 0 {
+		for i, j := 0, len(pts)-1; i < j; i, j = i+1, j-1 {
+			pts[i], pts[j] = pts[j], pts[i]
+		}
+	}
+	x, y := toFloat32(pts[0])
+	outline.MoveTo(x, y)
+	for _, pt := range pts[1:] {
+		x, y := toFloat32(pt)
+		outline.LineTo(x, y)
+	}
+	outline.ClosePath()
+}
+
+func signedArea(pts []fixed.Point26_6) float64 {
+	var sum float64
+	for i := range pts {
+		j := (i + 1) % len(pts)
+		sum += f64(pts[i].X)*f64(pts[j].Y) - f64(pts[j].X)*f64(pts[i].Y)
+	}
+	return sum
+}
+
+// patternImage resolves a fill/stroke Pattern to a uniform source image
+// for vector.Rasterizer.Draw. Only PlainColor is rendered: a gradient or
+// any other Pattern resolves to image.Transparent, so it draws nothing,
+// and no ErrUnsupported is recorded for it.
+func patternImage(p svgicon.Pattern, opacity float64) image.Image {
+	plain, ok := p.(svgicon.PlainColor)
+	if !ok {
+		return image.Transparent
+	}
+	c := color.NRGBA(plain)
+	c.A = uint8(float64(c.A) * opacity)
+	return image.NewUniform(c)
+}